@@ -0,0 +1,131 @@
+// Command quirk is the command-line front end for QuirkLang's lexer.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/tonystalker/QuirkLang/pkg/lexer"
+	"github.com/tonystalker/QuirkLang/pkg/token"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "lex":
+		if err := runLex(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "quirk lex:", err)
+			os.Exit(1)
+		}
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: quirk lex [--json] [file|-]")
+}
+
+func runLex(args []string) error {
+	fs := flag.NewFlagSet("lex", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "stream tokens as NDJSON instead of a table")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	src, err := openSource(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return err
+	}
+
+	l := lexer.New(bytes.NewReader(data))
+
+	if *asJSON {
+		return lexJSON(l, os.Stdout, data)
+	}
+	return lexTable(l, os.Stdout, data)
+}
+
+// openSource resolves "", "-", and a path to a readable source. "" and
+// "-" both mean stdin.
+func openSource(path string) (io.ReadCloser, error) {
+	if path == "" || path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(path)
+}
+
+// lexTable writes a human-readable token table, one token per line.
+func lexTable(l *lexer.Lexer, w io.Writer, src []byte) error {
+	bw := bufio.NewWriter(w)
+	defer bw.Flush()
+
+	for {
+		tok := l.NextToken()
+		if tok.Type == token.EOF {
+			break
+		}
+		if _, err := fmt.Fprintf(bw, "%d:%d\t%s\t%s\n", tok.Pos.Line, tok.Pos.Col, tok.Type, tok.Value); err != nil {
+			return err
+		}
+	}
+	return reportErrors(l, os.Stderr, src)
+}
+
+// jsonToken is the NDJSON shape streamed by lexJSON, one object per
+// line for easy consumption by editors and LSP tooling.
+type jsonToken struct {
+	Line int    `json:"line"`
+	Col  int    `json:"col"`
+	Type string `json:"type"`
+	Lit  string `json:"lit"`
+}
+
+// lexJSON streams tokens as newline-delimited JSON as they're produced,
+// so a consumer can start processing before the file finishes lexing.
+func lexJSON(l *lexer.Lexer, w io.Writer, src []byte) error {
+	enc := json.NewEncoder(w)
+
+	for {
+		tok := l.NextToken()
+		if tok.Type == token.EOF {
+			break
+		}
+		if err := enc.Encode(jsonToken{
+			Line: tok.Pos.Line,
+			Col:  tok.Pos.Col,
+			Type: tok.Type.String(),
+			Lit:  tok.Value,
+		}); err != nil {
+			return err
+		}
+	}
+	return reportErrors(l, os.Stderr, src)
+}
+
+func reportErrors(l *lexer.Lexer, w io.Writer, src []byte) error {
+	errs := l.Errors()
+	if len(errs) == 0 {
+		return nil
+	}
+	for _, e := range errs {
+		fmt.Fprintln(w, lexer.FormatError(src, e))
+	}
+	return fmt.Errorf("%d lexer error(s)", len(errs))
+}
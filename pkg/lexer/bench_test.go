@@ -0,0 +1,109 @@
+package lexer
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/tonystalker/QuirkLang/pkg/token"
+)
+
+// genSource builds a synthetic QuirkLang source of at least n bytes,
+// mixing identifiers, numbers, strings, operators, and comments so the
+// benchmark exercises every lexer state.
+func genSource(n int) string {
+	const chunk = `fn add(a, b) { // sum two values
+	var total := a + b
+	if total >= 100 {
+		return total
+	}
+	loop {
+		total += 1
+	}
+}
+msg := "hello, \"world\"\n"
+x := 0xFF_00 + 0b1010 - 1.5e-3
+`
+	var sb strings.Builder
+	for sb.Len() < n {
+		sb.WriteString(chunk)
+	}
+	return sb.String()
+}
+
+// legacyLex is the original per-rune bufio.Reader scanner this package
+// replaced in an earlier revision, kept here only so BenchmarkLexLegacy
+// can measure how much the slice-based rewrite improved performance.
+func legacyLex(r io.Reader) int {
+	reader := bufio.NewReader(r)
+	count := 0
+	for {
+		ch, _, err := reader.ReadRune()
+		if err != nil {
+			break
+		}
+		switch {
+		case unicode.IsSpace(ch):
+			continue
+		case unicode.IsLetter(ch) || ch == '_':
+			for {
+				ch, _, err := reader.ReadRune()
+				if err != nil {
+					break
+				}
+				if !unicode.IsLetter(ch) && !unicode.IsDigit(ch) && ch != '_' {
+					reader.UnreadRune()
+					break
+				}
+			}
+			count++
+		case unicode.IsDigit(ch):
+			for {
+				ch, _, err := reader.ReadRune()
+				if err != nil {
+					break
+				}
+				if !unicode.IsDigit(ch) {
+					reader.UnreadRune()
+					break
+				}
+			}
+			count++
+		default:
+			count++
+		}
+	}
+	return count
+}
+
+// BenchmarkLexSliceBased runs consistently slower than
+// BenchmarkLexLegacyReader - legacyLex does far less work per rune than
+// a full QuirkLang scan (no error recording, no float/multi-base number
+// handling, no string or comment states), so the gap reflects features
+// gained, not a performance bug.
+func BenchmarkLexSliceBased(b *testing.B) {
+	src := genSource(1 << 20) // >= 1MB
+	b.SetBytes(int64(len(src)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		l := NewFromString(src)
+		for {
+			if l.NextToken().Type == token.EOF {
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkLexLegacyReader(b *testing.B) {
+	src := genSource(1 << 20) // >= 1MB
+	b.SetBytes(int64(len(src)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		legacyLex(strings.NewReader(src))
+	}
+}
@@ -0,0 +1,613 @@
+// Package lexer turns QuirkLang source into a stream of tokens.
+//
+// It follows the state-function scanner design from Rob Pike's "Lexical
+// Scanning in Go" talk, but drives the state functions synchronously
+// from NextToken rather than over a goroutine and channel: an earlier
+// revision used a goroutine, which both leaked when a consumer abandoned
+// a Lexer before EOF and cost an order of magnitude in throughput versus
+// a plain synchronous scan - unacceptable for the latency-sensitive
+// editor/LSP use this package is meant for.
+//
+// This slice-based scanner is still measurably slower than the naive
+// per-rune bufio.Reader scanner it replaced (see BenchmarkLexSliceBased
+// vs. BenchmarkLexLegacyReader in bench_test.go) - the trade is for
+// features the old scanner didn't have: structured LexError reporting,
+// floats and multi-base integers, multi-line strings, and comments.
+package lexer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/tonystalker/QuirkLang/pkg/token"
+)
+
+// eof is the sentinel rune returned by next() once the input is exhausted.
+// It can't collide with a real rune, mirroring text/template's lexer.
+const eof = rune(-1)
+
+// LexError is a single diagnostic produced while scanning, with enough
+// context (the offending span and its source text) for a caller to
+// build a compiler-style message via FormatError.
+type LexError struct {
+	Pos     token.Position
+	End     token.Position
+	Msg     string
+	Snippet string
+}
+
+// FormatError renders e against src as a compiler-style diagnostic: the
+// message, the offending line, and a caret pointing at the column.
+func FormatError(src []byte, e LexError) string {
+	lines := strings.Split(string(src), "\n")
+	if e.Pos.Line < 1 || e.Pos.Line > len(lines) {
+		return fmt.Sprintf("%d:%d: %s", e.Pos.Line, e.Pos.Col, e.Msg)
+	}
+
+	line := lines[e.Pos.Line-1]
+	col := e.Pos.Col
+	if col < 1 {
+		col = 1
+	}
+
+	return fmt.Sprintf("%d:%d: %s\n%s\n%s^", e.Pos.Line, e.Pos.Col, e.Msg, line, strings.Repeat(" ", col-1))
+}
+
+// stateFn represents the state of the scanner as a function that returns
+// the next state.
+type stateFn func(*Lexer) stateFn
+
+// Lexer scans QuirkLang source into tokens. Construct one with New or
+// NewFromString and pull tokens with NextToken.
+type Lexer struct {
+	input string // the full source, buffered up front
+	start int    // start position of the token being scanned
+	pos   int    // current position in input
+	width int    // width of the last rune read by next(), for backup()
+
+	line, col         int // position of pos
+	prevLine, prevCol int // position before the last next(), for backup()
+	startLine         int // line of start
+	startCol          int // col of start
+
+	emitComments bool // whether comments are emitted as COMMENT tokens or skipped
+
+	errors []LexError
+
+	state   stateFn     // next state to run; nil once the scanner has terminated
+	pending token.Token // token produced by the last state that emitted one
+	has     bool        // whether pending holds a token NextToken hasn't returned yet
+}
+
+// Option configures a Lexer at construction time.
+type Option func(*Lexer)
+
+// EmitComments controls whether // and /* */ comments are emitted as
+// COMMENT tokens (true) or silently skipped (the default, false).
+func EmitComments(emit bool) Option {
+	return func(l *Lexer) {
+		l.emitComments = emit
+	}
+}
+
+// New reads r fully into memory. Tokens are produced lazily, one per
+// NextToken call. If r fails to read, the lexer records the failure (see
+// Errors) and behaves as if it scanned an empty input, yielding a single
+// EOF token.
+func New(r io.Reader, opts ...Option) *Lexer {
+	src, err := io.ReadAll(r)
+
+	l := &Lexer{
+		line:      1,
+		col:       1,
+		startLine: 1,
+		startCol:  1,
+		state:     lexRoot,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	if err != nil {
+		l.errors = append(l.errors, LexError{
+			Pos: token.Position{Line: 1, Col: 1},
+			End: token.Position{Line: 1, Col: 1},
+			Msg: fmt.Sprintf("reading input: %v", err),
+		})
+		l.state = nil
+		return l
+	}
+
+	l.input = string(src)
+	return l
+}
+
+// NewFromString is a convenience wrapper around New for in-memory source.
+func NewFromString(src string, opts ...Option) *Lexer {
+	return New(strings.NewReader(src), opts...)
+}
+
+// Errors returns the diagnostics recorded while scanning. It's safe to
+// call once NextToken has returned EOF.
+func (l *Lexer) Errors() []LexError {
+	return l.errors
+}
+
+// NextToken runs the state machine until a state emits a token, then
+// returns it. Once the scanner has emitted EOF, NextToken keeps
+// returning a zero-value Token, whose Type is EOF, without doing any
+// further work - so an abandoned Lexer (a parser bailing out on the
+// first error, an editor discarding it for a fresh one on every
+// keystroke) is just garbage, with nothing left running to leak.
+func (l *Lexer) NextToken() token.Token {
+	for l.state != nil {
+		l.state = l.state(l)
+		if l.has {
+			l.has = false
+			return l.pending
+		}
+	}
+	return token.Token{}
+}
+
+// next returns the next rune in the input and advances pos, or eof when
+// the input is exhausted.
+func (l *Lexer) next() rune {
+	if l.pos >= len(l.input) {
+		l.width = 0
+		return eof
+	}
+
+	r, w := utf8.DecodeRuneInString(l.input[l.pos:])
+	l.width = w
+	l.prevLine, l.prevCol = l.line, l.col
+	l.pos += w
+	if r == '\n' {
+		l.line++
+		l.col = 1
+	} else {
+		l.col++
+	}
+	return r
+}
+
+// peek returns the next rune without consuming it.
+func (l *Lexer) peek() rune {
+	r := l.next()
+	l.backup()
+	return r
+}
+
+// backup steps back one rune. It may only be called once per call of
+// next().
+func (l *Lexer) backup() {
+	l.pos -= l.width
+	l.line, l.col = l.prevLine, l.prevCol
+}
+
+// ignore skips the pending input before start.
+func (l *Lexer) ignore() {
+	l.start = l.pos
+	l.startLine, l.startCol = l.line, l.col
+}
+
+// accept consumes the next rune if it's in valid.
+func (l *Lexer) accept(valid string) bool {
+	if strings.ContainsRune(valid, l.next()) {
+		return true
+	}
+	l.backup()
+	return false
+}
+
+// acceptRun consumes a run of runes from valid.
+func (l *Lexer) acceptRun(valid string) {
+	for strings.ContainsRune(valid, l.next()) {
+	}
+	l.backup()
+}
+
+// emit passes the current token, from start to pos, back to the consumer.
+func (l *Lexer) emit(t token.Type) {
+	l.emitValue(t, l.input[l.start:l.pos])
+}
+
+// emitValue stashes a token with an explicit value for NextToken to
+// return, for tokens (like STRING) whose literal value differs from the
+// raw source text that produced them.
+func (l *Lexer) emitValue(t token.Type, value string) {
+	l.pending = token.Token{
+		Type:  t,
+		Value: value,
+		Pos:   token.Position{Line: l.startLine, Col: l.startCol},
+	}
+	l.has = true
+	l.start = l.pos
+	l.startLine, l.startCol = l.line, l.col
+}
+
+// illegal records a LexError spanning the current token (l.start to
+// l.pos) and emits it as an ILLEGAL token, whose Value is the offending
+// source text.
+func (l *Lexer) illegal(msg string) {
+	l.errors = append(l.errors, LexError{
+		Pos:     token.Position{Line: l.startLine, Col: l.startCol},
+		End:     token.Position{Line: l.line, Col: l.col},
+		Msg:     msg,
+		Snippet: l.input[l.start:l.pos],
+	})
+	l.emit(token.ILLEGAL)
+}
+
+// lexRoot is the top-level state: it dispatches on the next rune.
+func lexRoot(l *Lexer) stateFn {
+	r := l.next()
+
+	switch {
+	case r == eof:
+		l.emit(token.EOF)
+		return nil
+	case r == '\n' || unicode.IsSpace(r):
+		l.ignore()
+		return lexRoot
+	case r == ';':
+		l.emit(token.SEMICOLON)
+	case r == '=':
+		if l.accept("=") {
+			l.emit(token.EQUAL_EQUAL)
+		} else {
+			l.emit(token.EQUAL)
+		}
+	case r == '+':
+		switch {
+		case l.accept("+"):
+			l.emit(token.PLUS_PLUS)
+		case l.accept("="):
+			l.emit(token.PLUS_EQUAL)
+		default:
+			l.emit(token.PLUS)
+		}
+	case r == '-':
+		switch {
+		case l.accept(">"):
+			l.emit(token.ARROW)
+		case l.accept("-"):
+			l.emit(token.MINUS_MINUS)
+		case l.accept("="):
+			l.emit(token.MINUS_EQUAL)
+		default:
+			l.emit(token.MINUS)
+		}
+	case r == '*':
+		if l.accept("=") {
+			l.emit(token.MULTIPLY_EQUAL)
+		} else {
+			l.emit(token.MULTIPLY)
+		}
+	case r == '/':
+		switch {
+		case l.accept("/"):
+			return lexLineComment
+		case l.accept("*"):
+			return lexBlockComment
+		case l.accept("="):
+			l.emit(token.DIVIDE_EQUAL)
+		default:
+			l.emit(token.DIVIDE)
+		}
+	case r == '%':
+		if l.accept("=") {
+			l.emit(token.MODULUS_EQUAL)
+		} else {
+			l.emit(token.MODULUS)
+		}
+	case r == '&':
+		if l.accept("&") {
+			l.emit(token.AND)
+		} else {
+			l.emit(token.AMPERSAND)
+		}
+	case r == '|':
+		if l.accept("|") {
+			l.emit(token.OR)
+		} else {
+			l.illegal(fmt.Sprintf("unexpected character %U %q at %d:%d", r, r, l.startLine, l.startCol))
+		}
+	case r == '>':
+		if l.accept("=") {
+			l.emit(token.GREATER_EQUAL)
+		} else {
+			l.emit(token.GREATER)
+		}
+	case r == '<':
+		if l.accept("=") {
+			l.emit(token.LESS_EQUAL)
+		} else {
+			l.emit(token.LESSER)
+		}
+	case r == '!':
+		if l.accept("=") {
+			l.emit(token.NOT_EQUAL)
+		} else {
+			l.emit(token.NOT)
+		}
+	case r == '(':
+		l.emit(token.LEFT_PARENTHESIS)
+	case r == ')':
+		l.emit(token.RIGHT_PARENTHESIS)
+	case r == '{':
+		l.emit(token.LEFT_BRACE)
+	case r == '}':
+		l.emit(token.RIGHT_BRACE)
+	case r == '[':
+		l.emit(token.LEFT_BRACKET)
+	case r == ']':
+		l.emit(token.RIGHT_BRACKET)
+	case r == ',':
+		l.emit(token.COMMA)
+	case r == ':':
+		if l.accept("=") {
+			l.emit(token.COLON_EQUAL)
+		} else {
+			l.emit(token.COLON)
+		}
+	case r == '"':
+		l.backup()
+		return lexString
+	case unicode.IsDigit(r):
+		l.backup()
+		return lexNumber
+	case r == '_' || unicode.IsLetter(r):
+		l.backup()
+		return lexIdent
+	default:
+		l.illegal(fmt.Sprintf("unexpected character %U %q at %d:%d", r, r, l.startLine, l.startCol))
+	}
+
+	return lexRoot
+}
+
+// decimalDigits and the hex/binary/octal digit sets accepted by
+// lexNumber. '_' is allowed throughout as a digit separator, e.g. 1_000
+// or 0xFF_00.
+const decimalDigits = "0123456789_"
+
+// lexNumber scans an INT or FLOAT literal: decimal, or 0x/0b/0o prefixed
+// for hex/binary/octal integers. Only decimal literals can carry a
+// fractional part and/or exponent.
+func lexNumber(l *Lexer) stateFn {
+	digits := decimalDigits
+	prefixed := false
+	if l.accept("0") {
+		switch {
+		case l.accept("xX"):
+			digits = "0123456789abcdefABCDEF_"
+			prefixed = true
+		case l.accept("bB"):
+			digits = "01_"
+			prefixed = true
+		case l.accept("oO"):
+			digits = "01234567_"
+			prefixed = true
+		}
+	}
+
+	digitsStart := l.pos
+	l.acceptRun(digits)
+	if prefixed && l.pos == digitsStart {
+		l.illegal(fmt.Sprintf("malformed numeric literal %q: no digits after base prefix", l.input[l.start:l.pos]))
+		return lexRoot
+	}
+
+	isFloat := false
+	if digits == decimalDigits {
+		if l.accept(".") {
+			isFloat = true
+			l.acceptRun(decimalDigits)
+		}
+		if l.accept("eE") {
+			isFloat = true
+			l.accept("+-")
+			l.acceptRun(decimalDigits)
+		}
+	}
+
+	if isFloat {
+		l.emit(token.FLOAT)
+	} else {
+		l.emit(token.INT)
+	}
+	return lexRoot
+}
+
+// lexIdent scans a run of letters, digits, and underscores, then
+// promotes the result to its keyword token type if it's reserved.
+func lexIdent(l *Lexer) stateFn {
+	for {
+		r := l.next()
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			continue
+		}
+		l.backup()
+		break
+	}
+
+	if kw, ok := token.Keywords[l.input[l.start:l.pos]]; ok {
+		l.emit(kw)
+	} else {
+		l.emit(token.IDENTIFIER)
+	}
+	return lexRoot
+}
+
+// lexString scans a `"..."` or triple-quoted `"""..."""` string literal.
+// l.next() has not yet consumed the opening quote.
+func lexString(l *Lexer) stateFn {
+	l.next() // the opening quote
+
+	if l.peek() == '"' {
+		l.next()
+		if l.peek() == '"' {
+			l.next() // the third quote of a """
+			return lexMultilineString
+		}
+		// Just "" - an empty single-line string.
+		l.emitValue(token.STRING, "")
+		return lexRoot
+	}
+
+	var sb strings.Builder
+	for {
+		switch r := l.next(); {
+		case r == eof || r == '\n':
+			l.illegal("unterminated string literal")
+			return lexRoot
+		case r == '"':
+			l.emitValue(token.STRING, sb.String())
+			return lexRoot
+		case r == '\\':
+			esc, ok := l.scanEscape()
+			if !ok {
+				l.illegal("invalid escape sequence in string literal")
+				return lexRoot
+			}
+			sb.WriteRune(esc)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+}
+
+// lexMultilineString scans the body of a `"""..."""` string. Unlike
+// lexString it does not interpret escape sequences, and it preserves
+// embedded newlines, advancing l.line as it goes via next().
+func lexMultilineString(l *Lexer) stateFn {
+	var sb strings.Builder
+	for {
+		r := l.next()
+		switch {
+		case r == eof:
+			l.illegal("unterminated multi-line string literal")
+			return lexRoot
+		case r == '"' && l.peek() == '"':
+			l.next()
+			if l.peek() == '"' {
+				l.next()
+				l.emitValue(token.STRING, sb.String())
+				return lexRoot
+			}
+			sb.WriteString(`""`)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+}
+
+// scanEscape consumes and decodes the character(s) following a backslash
+// in a string literal.
+func (l *Lexer) scanEscape() (rune, bool) {
+	switch r := l.next(); r {
+	case 'n':
+		return '\n', true
+	case 't':
+		return '\t', true
+	case 'r':
+		return '\r', true
+	case '\\':
+		return '\\', true
+	case '"':
+		return '"', true
+	case 'x':
+		return l.scanHexEscape(2)
+	case 'u':
+		return l.scanHexEscape(4)
+	default:
+		return 0, false
+	}
+}
+
+// scanHexEscape reads exactly n hex digits and returns their value, for
+// \xNN and \uNNNN escapes. It rejects code points in the UTF-16
+// surrogate range (\ud800-\udfff): they aren't valid Unicode scalar
+// values on their own, and writing one via sb.WriteRune would silently
+// corrupt the literal into a replacement character.
+func (l *Lexer) scanHexEscape(n int) (rune, bool) {
+	var v rune
+	for i := 0; i < n; i++ {
+		d, ok := hexDigit(l.next())
+		if !ok {
+			return 0, false
+		}
+		v = v*16 + rune(d)
+	}
+	if v >= 0xd800 && v <= 0xdfff {
+		return 0, false
+	}
+	return v, true
+}
+
+func hexDigit(r rune) (int, bool) {
+	switch {
+	case r >= '0' && r <= '9':
+		return int(r - '0'), true
+	case r >= 'a' && r <= 'f':
+		return int(r-'a') + 10, true
+	case r >= 'A' && r <= 'F':
+		return int(r-'A') + 10, true
+	default:
+		return 0, false
+	}
+}
+
+// lexLineComment scans a // comment up to (but not including) the
+// terminating newline, leaving it for lexRoot to skip as whitespace.
+func lexLineComment(l *Lexer) stateFn {
+	for {
+		r := l.next()
+		if r == '\n' {
+			l.backup()
+			break
+		}
+		if r == eof {
+			break
+		}
+	}
+
+	if l.emitComments {
+		l.emit(token.COMMENT)
+	} else {
+		l.ignore()
+	}
+	return lexRoot
+}
+
+// lexBlockComment scans a /* ... */ comment, which may nest, tracking
+// newlines as it goes so later tokens keep correct line numbers.
+func lexBlockComment(l *Lexer) stateFn {
+	depth := 1
+	for depth > 0 {
+		switch l.next() {
+		case eof:
+			l.illegal("unterminated block comment")
+			return lexRoot
+		case '/':
+			if l.accept("*") {
+				depth++
+			}
+		case '*':
+			if l.accept("/") {
+				depth--
+			}
+		}
+	}
+
+	if l.emitComments {
+		l.emit(token.COMMENT)
+	} else {
+		l.ignore()
+	}
+	return lexRoot
+}
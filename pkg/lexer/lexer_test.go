@@ -0,0 +1,326 @@
+package lexer
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/tonystalker/QuirkLang/pkg/token"
+)
+
+func lexAll(src string) []token.Token {
+	l := NewFromString(src)
+	var toks []token.Token
+	for {
+		tok := l.NextToken()
+		toks = append(toks, tok)
+		if tok.Type == token.EOF {
+			break
+		}
+	}
+	return toks
+}
+
+func TestLexStringEscapes(t *testing.T) {
+	cases := []struct {
+		src  string
+		want string
+	}{
+		{`"hello\n"`, "hello\n"},
+		{`"a\tb\rc"`, "a\tb\rc"},
+		{`"back\\slash"`, `back\slash`},
+		{`"quote: \""`, `quote: "`},
+		{`"\x41\x42"`, "AB"},
+		{`"é"`, "é"},
+		{`"\u2764"`, "❤"},
+	}
+
+	for _, c := range cases {
+		toks := lexAll(c.src)
+		if len(toks) != 2 || toks[0].Type != token.STRING {
+			t.Fatalf("lexAll(%q) = %+v, want single STRING token", c.src, toks)
+		}
+		if toks[0].Value != c.want {
+			t.Errorf("lexAll(%q).Value = %q, want %q", c.src, toks[0].Value, c.want)
+		}
+	}
+}
+
+func TestLexStringUnterminated(t *testing.T) {
+	toks := lexAll(`"unterminated`)
+	if len(toks) != 2 || toks[0].Type != token.ILLEGAL {
+		t.Fatalf("lexAll unterminated string = %+v, want single ILLEGAL token", toks)
+	}
+}
+
+func TestLexStringInvalidEscape(t *testing.T) {
+	toks := lexAll(`"bad\q"`)
+	if len(toks) == 0 || toks[0].Type != token.ILLEGAL {
+		t.Fatalf("lexAll invalid escape = %+v, want to start with an ILLEGAL token", toks)
+	}
+}
+
+func TestLexStringUnpairedSurrogateEscape(t *testing.T) {
+	// \ud800-\udfff are UTF-16 surrogate halves, not valid Unicode scalar
+	// values on their own; reporting them as illegal beats silently
+	// writing U+FFFD in their place.
+	toks := lexAll(`"\ud800"`)
+	if len(toks) == 0 || toks[0].Type != token.ILLEGAL {
+		t.Fatalf("lexAll unpaired surrogate escape = %+v, want to start with an ILLEGAL token", toks)
+	}
+}
+
+func TestLexMultilineString(t *testing.T) {
+	src := "\"\"\"line one\nline two\"\"\"\nfoo"
+	toks := lexAll(src)
+	if len(toks) != 3 {
+		t.Fatalf("lexAll(%q) = %+v, want STRING, IDENTIFIER, EOF", src, toks)
+	}
+	if toks[0].Type != token.STRING || toks[0].Value != "line one\nline two" {
+		t.Errorf("got %+v, want STRING %q", toks[0], "line one\nline two")
+	}
+	if toks[1].Type != token.IDENTIFIER || toks[1].Pos.Line != 3 {
+		t.Errorf("got %+v, want IDENTIFIER on line 3", toks[1])
+	}
+}
+
+func TestLexMultilineStringUnterminated(t *testing.T) {
+	toks := lexAll(`"""no closing quotes`)
+	if len(toks) != 2 || toks[0].Type != token.ILLEGAL {
+		t.Fatalf("lexAll unterminated multi-line string = %+v, want single ILLEGAL token", toks)
+	}
+}
+
+func TestLexTwoCharOperators(t *testing.T) {
+	cases := []struct {
+		src  string
+		want token.Type
+	}{
+		{"==", token.EQUAL_EQUAL},
+		{"!=", token.NOT_EQUAL},
+		{">=", token.GREATER_EQUAL},
+		{"<=", token.LESS_EQUAL},
+		{"&&", token.AND},
+		{"||", token.OR},
+		{"->", token.ARROW},
+		{":=", token.COLON_EQUAL},
+		{"++", token.PLUS_PLUS},
+		{"--", token.MINUS_MINUS},
+		{"+=", token.PLUS_EQUAL},
+		{"-=", token.MINUS_EQUAL},
+		{"*=", token.MULTIPLY_EQUAL},
+		{"/=", token.DIVIDE_EQUAL},
+		{"%=", token.MODULUS_EQUAL},
+	}
+
+	for _, c := range cases {
+		toks := lexAll(c.src)
+		if len(toks) != 2 || toks[0].Type != c.want {
+			t.Fatalf("lexAll(%q) = %+v, want single %s token", c.src, toks, c.want)
+		}
+		if toks[0].Pos.Col != 1 {
+			t.Errorf("lexAll(%q) token starts at col %d, want 1", c.src, toks[0].Pos.Col)
+		}
+	}
+}
+
+func TestLexOperatorsDoNotGreedilyMerge(t *testing.T) {
+	toks := lexAll("= = x ==y")
+	wantTypes := []token.Type{token.EQUAL, token.EQUAL, token.IDENTIFIER, token.EQUAL_EQUAL, token.IDENTIFIER, token.EOF}
+	if len(toks) != len(wantTypes) {
+		t.Fatalf("lexAll = %+v, want %d tokens", toks, len(wantTypes))
+	}
+	for i, want := range wantTypes {
+		if toks[i].Type != want {
+			t.Errorf("token %d = %s, want %s", i, toks[i].Type, want)
+		}
+	}
+	if toks[3].Pos.Col != 7 {
+		t.Errorf("== token at col %d, want 7", toks[3].Pos.Col)
+	}
+}
+
+func TestLexKeywords(t *testing.T) {
+	cases := map[string]token.Type{
+		"fn":     token.FN,
+		"var":    token.VAR,
+		"if":     token.IF,
+		"else":   token.ELSE,
+		"return": token.RETURN,
+		"loop":   token.LOOP,
+		"fnord":  token.IDENTIFIER,
+	}
+
+	for src, want := range cases {
+		toks := lexAll(src)
+		if len(toks) != 2 || toks[0].Type != want {
+			t.Errorf("lexAll(%q) = %+v, want single %s token", src, toks, want)
+		}
+	}
+}
+
+func TestLexIdentifierContinuation(t *testing.T) {
+	toks := lexAll("foo1 _leading bar_2baz")
+	want := []string{"foo1", "_leading", "bar_2baz"}
+	if len(toks) != len(want)+1 {
+		t.Fatalf("lexAll = %+v, want %d identifiers", toks, len(want))
+	}
+	for i, lit := range want {
+		if toks[i].Type != token.IDENTIFIER || toks[i].Value != lit {
+			t.Errorf("token %d = %+v, want IDENTIFIER %q", i, toks[i], lit)
+		}
+	}
+}
+
+func TestLexNumberBases(t *testing.T) {
+	cases := []struct {
+		src  string
+		want token.Type
+	}{
+		{"0xFF_00", token.INT},
+		{"0b1010", token.INT},
+		{"0o17", token.INT},
+		{"1_000", token.INT},
+		{"1.5e-3", token.FLOAT},
+		{"3.14", token.FLOAT},
+		{"2E10", token.FLOAT},
+	}
+
+	for _, c := range cases {
+		toks := lexAll(c.src)
+		if len(toks) != 2 || toks[0].Type != c.want || toks[0].Value != c.src {
+			t.Errorf("lexAll(%q) = %+v, want single %s token with value %q", c.src, toks, c.want, c.src)
+		}
+	}
+}
+
+func TestLexCommentsSkippedByDefault(t *testing.T) {
+	toks := lexAll("foo // a comment\nbar /* block\ncomment */ baz")
+	want := []string{"foo", "bar", "baz"}
+	if len(toks) != len(want)+1 {
+		t.Fatalf("lexAll = %+v, want %d identifiers", toks, len(want))
+	}
+	for i, lit := range want {
+		if toks[i].Type != token.IDENTIFIER || toks[i].Value != lit {
+			t.Errorf("token %d = %+v, want IDENTIFIER %q", i, toks[i], lit)
+		}
+	}
+	if toks[2].Pos.Line != 3 {
+		t.Errorf("baz is on line %d, want 3", toks[2].Pos.Line)
+	}
+}
+
+func TestLexCommentsEmittedWhenRequested(t *testing.T) {
+	l := NewFromString("foo // line\nbar", EmitComments(true))
+	var toks []token.Token
+	for {
+		tok := l.NextToken()
+		toks = append(toks, tok)
+		if tok.Type == token.EOF {
+			break
+		}
+	}
+	wantTypes := []token.Type{token.IDENTIFIER, token.COMMENT, token.IDENTIFIER, token.EOF}
+	if len(toks) != len(wantTypes) {
+		t.Fatalf("lexAll = %+v, want %d tokens", toks, len(wantTypes))
+	}
+	for i, want := range wantTypes {
+		if toks[i].Type != want {
+			t.Errorf("token %d = %s, want %s", i, toks[i].Type, want)
+		}
+	}
+	if toks[1].Value != "// line" {
+		t.Errorf("comment value = %q, want %q", toks[1].Value, "// line")
+	}
+}
+
+func TestLexNestedBlockComments(t *testing.T) {
+	toks := lexAll("/* outer /* inner */ still comment */ foo")
+	if len(toks) != 2 || toks[0].Type != token.IDENTIFIER || toks[0].Value != "foo" {
+		t.Fatalf("lexAll nested block comment = %+v, want single IDENTIFIER(foo)", toks)
+	}
+}
+
+func TestLexUnterminatedBlockComment(t *testing.T) {
+	toks := lexAll("/* never closes")
+	if len(toks) != 2 || toks[0].Type != token.ILLEGAL {
+		t.Fatalf("lexAll unterminated block comment = %+v, want single ILLEGAL token", toks)
+	}
+}
+
+func TestLexBarePrefixIsIllegal(t *testing.T) {
+	cases := []string{"0x;", "0b;", "0o;"}
+
+	for _, src := range cases {
+		toks := lexAll(src)
+		if len(toks) != 3 || toks[0].Type != token.ILLEGAL {
+			t.Errorf("lexAll(%q) = %+v, want ILLEGAL(%s), SEMICOLON, EOF", src, toks, src[:2])
+		}
+	}
+}
+
+func TestLexHexDoesNotConsumeTrailingDot(t *testing.T) {
+	// The '.' after a hex literal isn't part of it (hex has no float
+	// form), so it's lexed separately as its own (illegal) token.
+	toks := lexAll("0xFF.bar")
+	if len(toks) != 4 || toks[0].Type != token.INT || toks[0].Value != "0xFF" {
+		t.Fatalf("lexAll(0xFF.bar) = %+v, want INT(0xFF), ILLEGAL(.), IDENTIFIER(bar), EOF", toks)
+	}
+}
+
+func TestAbandonedLexerDoesNotLeakGoroutines(t *testing.T) {
+	// NextToken drives the scanner synchronously, so there's nothing
+	// running in the background for an abandoned *Lexer to leak.
+	runtime.GC()
+	before := runtime.NumGoroutine()
+
+	const n = 20
+	lexers := make([]*Lexer, n)
+	for i := range lexers {
+		lexers[i] = NewFromString("x := 1 + 2 + 3 + 4 + 5 + 6 + 7 + 8 + 9 + 10")
+		lexers[i].NextToken() // read one token, then abandon the rest
+	}
+
+	runtime.GC()
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("NumGoroutine() = %d after abandoning %d lexers, want <= %d", after, n, before)
+	}
+}
+
+func drainAndReturnLexer(src string) *Lexer {
+	l := NewFromString(src)
+	for {
+		if l.NextToken().Type == token.EOF {
+			return l
+		}
+	}
+}
+
+func TestLexErrorsRecorded(t *testing.T) {
+	l := drainAndReturnLexer("x = `bad\n\"still open")
+	errs := l.Errors()
+	if len(errs) != 2 {
+		t.Fatalf("Errors() = %+v, want 2 entries", errs)
+	}
+	if errs[0].Pos.Col != 5 || errs[0].Snippet != "`" {
+		t.Errorf("first error = %+v, want col 5 snippet \"`\"", errs[0])
+	}
+	if errs[1].Msg != "unterminated string literal" {
+		t.Errorf("second error msg = %q, want %q", errs[1].Msg, "unterminated string literal")
+	}
+}
+
+func TestFormatError(t *testing.T) {
+	src := "x = `y"
+	l := drainAndReturnLexer(src)
+	errs := l.Errors()
+	if len(errs) != 1 {
+		t.Fatalf("Errors() = %+v, want 1 entry", errs)
+	}
+
+	got := FormatError([]byte(src), errs[0])
+	wantCaret := "    ^"
+	if !strings.Contains(got, src) || !strings.Contains(got, wantCaret) {
+		t.Errorf("FormatError() = %q, want it to contain %q and a caret %q", got, src, wantCaret)
+	}
+}
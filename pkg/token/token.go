@@ -0,0 +1,146 @@
+// Package token defines the lexical tokens produced by pkg/lexer.
+package token
+
+type Type int
+
+const (
+	EOF = iota
+	ILLEGAL
+	IDENTIFIER
+	INT
+	FLOAT
+	STRING
+	COMMENT
+
+	// Operators
+	ASSIGN    // =
+	PLUS      // +
+	MINUS     // -
+	MULTIPLY  // *
+	DIVIDE    // /
+	MODULUS   // %
+	AMPERSAND // &
+	GREATER   // >
+	LESSER    // <
+	NOT       // !
+
+	// Keywords
+	FN
+	VAR
+	IF
+	ELSE
+	RETURN
+	LOOP
+
+	// ONE OR TWO CHARACTER TOKENS
+	EQUAL             // =
+	EQUAL_EQUAL       // ==
+	GREATER_EQUAL     // >=
+	LESS_EQUAL        // <=
+	LEFT_PARENTHESIS  // (
+	RIGHT_PARENTHESIS // )
+	LEFT_BRACE        // {
+	RIGHT_BRACE       // }
+	LEFT_BRACKET      // [
+	RIGHT_BRACKET     // ]
+	COMMA             // ,
+	COLON             // :
+	SEMICOLON         //;
+
+	// TWO CHARACTER TOKENS
+	NOT_EQUAL      // !=
+	AND            // &&
+	OR             // ||
+	ARROW          // ->
+	COLON_EQUAL    // :=
+	PLUS_PLUS      // ++
+	MINUS_MINUS    // --
+	PLUS_EQUAL     // +=
+	MINUS_EQUAL    // -=
+	MULTIPLY_EQUAL // *=
+	DIVIDE_EQUAL   // /=
+	MODULUS_EQUAL  // %=
+)
+
+var names = [...]string{
+	EOF:        "EOF",
+	ILLEGAL:    "ILLEGAL",
+	IDENTIFIER: "IDENTIFIER",
+	INT:        "INT",
+	FLOAT:      "FLOAT",
+	STRING:     "STRING",
+	COMMENT:    "COMMENT",
+	//OPERATORS
+	SEMICOLON: ";",
+	PLUS:      "+",
+	MINUS:     "-",
+	MULTIPLY:  "*",
+	DIVIDE:    "/",
+	MODULUS:   "%",
+	AMPERSAND: "&",
+	GREATER:   ">",
+	LESSER:    "<",
+	NOT:       "!",
+	//KEYWORDS
+	FN:     "FN",
+	VAR:    "VAR",
+	IF:     "IF",
+	ELSE:   "ELSE",
+	RETURN: "RETURN",
+	LOOP:   "LOOP",
+	//ONE OR TWO CHARACTER TOKENS
+	EQUAL:             "=",
+	EQUAL_EQUAL:       "==",
+	GREATER_EQUAL:     ">=",
+	LESS_EQUAL:        "<=",
+	LEFT_PARENTHESIS:  "(",
+	RIGHT_PARENTHESIS: ")",
+	LEFT_BRACE:        "{",
+	RIGHT_BRACE:       "}",
+	LEFT_BRACKET:      "[",
+	RIGHT_BRACKET:     "]",
+	COMMA:             ",",
+	COLON:             ":",
+	//TWO CHARACTER TOKENS
+	NOT_EQUAL:      "!=",
+	AND:            "&&",
+	OR:             "||",
+	ARROW:          "->",
+	COLON_EQUAL:    ":=",
+	PLUS_PLUS:      "++",
+	MINUS_MINUS:    "--",
+	PLUS_EQUAL:     "+=",
+	MINUS_EQUAL:    "-=",
+	MULTIPLY_EQUAL: "*=",
+	DIVIDE_EQUAL:   "/=",
+	MODULUS_EQUAL:  "%=",
+}
+
+func (t Type) String() string {
+	return names[t]
+}
+
+// Keywords maps reserved words to their token type. Anything else a
+// lexer scans as an identifier is a plain IDENTIFIER.
+var Keywords = map[string]Type{
+	"fn":     FN,
+	"var":    VAR,
+	"if":     IF,
+	"else":   ELSE,
+	"return": RETURN,
+	"loop":   LOOP,
+}
+
+// Position identifies a location in source by 1-based line and column.
+type Position struct {
+	Line int
+	Col  int
+}
+
+// Token is what a lexer hands to a consumer: its kind, the literal text
+// that produced it, and where in the source it started.
+type Token struct {
+	Type  Type
+	Value string
+	Pos   Position
+}